@@ -0,0 +1,112 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ScopeAttributeBatchResolver may optionally be implemented by a ScopeAttributeResolver to resolve many scopes
+// sharing a prefix in a single call. Resolvers backed by a database or HTTP lookup should implement this to
+// avoid one round trip per scope when evaluating a large result set (e.g. a dashboard search).
+type ScopeAttributeBatchResolver interface {
+	ResolveBatch(ctx context.Context, orgID int64, scopes []string) (map[string][]string, error)
+}
+
+// ScopeAttributeBatchMutator resolves many scopes at once, returning the resolved scopes keyed by the input
+// scope they were resolved from.
+type ScopeAttributeBatchMutator func(context.Context, []string) (map[string][]string, error)
+
+// GetScopeAttributeBatchMutator groups scopes by ScopePrefix, serves cache hits directly, and resolves cache
+// misses per prefix in a single ResolveBatch call when the registered resolver supports it, falling back to
+// resolving each scope individually otherwise. Misses are also checked against the negative error cache (see
+// ResolverErrorPolicy) before resolving, and a prefix group's failure is cached there the same way a single
+// GetScopeAttributeMutator failure would be -- otherwise the batch path, which exists precisely for the
+// high-fanout case (e.g. a dashboard search returning 500 results), would hit a struggling resolver on every
+// call with none of the backoff protection the single-scope path gets.
+//
+// A failure resolving one prefix group doesn't drop results already resolved for other groups: errors are
+// accumulated per prefix and joined together, while every scope that did resolve is still returned. A single
+// bad scope in a large batch therefore doesn't fail permission evaluation for the other 499.
+func (s *Resolvers) GetScopeAttributeBatchMutator(orgID int64) ScopeAttributeBatchMutator {
+	return func(ctx context.Context, scopes []string) (map[string][]string, error) {
+		resolved := make(map[string][]string, len(scopes))
+		missesByPrefix := map[string][]string{}
+		var errs []error
+
+		for _, scope := range scopes {
+			prefix := ScopePrefix(scope)
+			key := getScopeCacheKey(orgID, scope)
+			if cached, ok := s.cache.Get(ctx, prefix, key); ok {
+				resolved[scope] = cached
+				continue
+			}
+			if s.errPolicy.CacheErrors {
+				if cachedErr, ok := s.errCache.get(key); ok {
+					errs = append(errs, cachedErr)
+					continue
+				}
+			}
+			missesByPrefix[prefix] = append(missesByPrefix[prefix], scope)
+		}
+
+		for prefix, misses := range missesByPrefix {
+			resolver, ok := s.resolverFor(orgID, prefix)
+			if !ok {
+				errs = append(errs, fmt.Errorf("could not resolve %v: %w", prefix, ErrResolverNotFound))
+				continue
+			}
+
+			scopesByInput, err := s.resolveBatch(ctx, orgID, resolver, misses)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not resolve %v: %w", prefix, err))
+			}
+
+			for scope, resolvedScopes := range scopesByInput {
+				key := getScopeCacheKey(orgID, scope)
+				if s.errPolicy.CacheErrors {
+					s.errCache.clear(key)
+				}
+				s.cache.Set(ctx, prefix, key, resolvedScopes, ttl)
+				resolved[scope] = resolvedScopes
+			}
+
+			// Only negative-cache the scopes that actually failed to resolve, not every scope in the miss
+			// group -- a scope that resolved fine alongside a failing one shouldn't have its success poisoned
+			// by the group's error on the next call.
+			if err != nil && s.errPolicy.CacheErrors && s.errPolicy.ShouldCache(err) {
+				wrapped := fmt.Errorf("could not resolve %v: %w", prefix, err)
+				for _, scope := range misses {
+					if _, ok := scopesByInput[scope]; ok {
+						continue
+					}
+					s.errCache.recordFailure(getScopeCacheKey(orgID, scope), wrapped, s.errPolicy.MinBackoff, s.errPolicy.MaxBackoff)
+				}
+			}
+		}
+
+		return resolved, errors.Join(errs...)
+	}
+}
+
+// resolveBatch calls ResolveBatch when resolver implements ScopeAttributeBatchResolver, and otherwise falls
+// back to calling Resolve once per scope. In the fallback case, one scope's error doesn't abort the others:
+// every scope that resolves successfully is still returned, alongside a joined error covering the ones that
+// didn't, so a single bad scope in a large prefix group can't wipe out the rest of that group's results.
+func (s *Resolvers) resolveBatch(ctx context.Context, orgID int64, resolver ScopeAttributeResolver, scopes []string) (map[string][]string, error) {
+	if batch, ok := resolver.(ScopeAttributeBatchResolver); ok {
+		return batch.ResolveBatch(ctx, orgID, scopes)
+	}
+
+	resolved := make(map[string][]string, len(scopes))
+	var errs []error
+	for _, scope := range scopes {
+		scopeResolution, err := resolver.Resolve(ctx, orgID, scope)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", scope, err))
+			continue
+		}
+		resolved[scope] = scopeResolution
+	}
+	return resolved, errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type fakeBatchResolver struct {
+	resolve      func(ctx context.Context, orgID int64, scope string) ([]string, error)
+	resolveBatch func(ctx context.Context, orgID int64, scopes []string) (map[string][]string, error)
+}
+
+func (f *fakeBatchResolver) Resolve(ctx context.Context, orgID int64, scope string) ([]string, error) {
+	return f.resolve(ctx, orgID, scope)
+}
+
+func (f *fakeBatchResolver) ResolveBatch(ctx context.Context, orgID int64, scopes []string) (map[string][]string, error) {
+	return f.resolveBatch(ctx, orgID, scopes)
+}
+
+func TestResolveBatch_FallsBackToResolveWhenNotImplemented(t *testing.T) {
+	var resolvedOneByOne []string
+	resolver := ScopeAttributeResolverFunc(func(_ context.Context, _ int64, scope string) ([]string, error) {
+		resolvedOneByOne = append(resolvedOneByOne, scope)
+		return []string{scope + ":resolved"}, nil
+	})
+
+	r := NewResolvers(log.NewNopLogger())
+	defer r.Close()
+
+	result, err := r.resolveBatch(context.Background(), 1, resolver, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a:resolved"}, result["a"])
+	require.Equal(t, []string{"b:resolved"}, result["b"])
+	require.ElementsMatch(t, []string{"a", "b"}, resolvedOneByOne, "each scope should have been resolved individually")
+}
+
+func TestResolveBatch_UsesResolveBatchWhenImplemented(t *testing.T) {
+	batchCalled := false
+	resolver := &fakeBatchResolver{
+		resolve: func(context.Context, int64, string) ([]string, error) {
+			t.Fatal("Resolve should not be called when ResolveBatch is implemented")
+			return nil, nil
+		},
+		resolveBatch: func(_ context.Context, _ int64, scopes []string) (map[string][]string, error) {
+			batchCalled = true
+			out := make(map[string][]string, len(scopes))
+			for _, scope := range scopes {
+				out[scope] = []string{scope + ":batch"}
+			}
+			return out, nil
+		},
+	}
+
+	r := NewResolvers(log.NewNopLogger())
+	defer r.Close()
+
+	result, err := r.resolveBatch(context.Background(), 1, resolver, []string{"a", "b"})
+	require.NoError(t, err)
+	require.True(t, batchCalled)
+	require.Equal(t, []string{"a:batch"}, result["a"])
+	require.Equal(t, []string{"b:batch"}, result["b"])
+}
+
+func TestGetScopeAttributeBatchMutator_PartialFailureKeepsOtherResults(t *testing.T) {
+	r := NewResolvers(log.NewNopLogger())
+	defer r.Close()
+
+	r.AddScopeAttributeResolver("dashboards:id:", ScopeAttributeResolverFunc(
+		func(_ context.Context, _ int64, _ string) ([]string, error) {
+			return []string{"dashboards:uid:ok"}, nil
+		}))
+	r.AddScopeAttributeResolver("folders:id:", ScopeAttributeResolverFunc(
+		func(_ context.Context, _ int64, _ string) ([]string, error) {
+			return nil, errors.New("boom")
+		}))
+
+	mutate := r.GetScopeAttributeBatchMutator(1)
+	result, err := mutate(context.Background(), []string{"dashboards:id:1", "folders:id:1"})
+
+	require.Error(t, err, "a failing prefix group's error should still be surfaced")
+	require.Equal(t, []string{"dashboards:uid:ok"}, result["dashboards:id:1"],
+		"the other prefix group's result should survive the failure")
+	_, ok := result["folders:id:1"]
+	require.False(t, ok)
+}
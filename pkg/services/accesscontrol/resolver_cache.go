@@ -0,0 +1,196 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+// ScopeResolutionCache caches the result of resolving a scope attribute (e.g. "dashboards:id:1") into the
+// concrete scopes an evaluator understands (e.g. "dashboards:uid:test-dashboard"). Implementations back
+// Resolvers and may be local to the instance or shared across an HA deployment.
+//
+// Get/Set/Delete take the resolver prefix (e.g. "dashboards:uid:") alongside the cache key rather than
+// deriving it from the key string: a key can carry a ResolveOption-derived suffix (an action like
+// "dashboards:read", a user UID like "user:7") that itself contains colons, and deriving the prefix from the
+// composite key string would file the entry under a bogus, one-off prefix that InvalidatePrefix can never
+// match. Callers must pass the prefix computed from the original scope, not from the key.
+type ScopeResolutionCache interface {
+	// Get returns the scopes cached for key under prefix, if any.
+	Get(ctx context.Context, prefix, key string) ([]string, bool)
+	// Set caches scopes for key under prefix until ttl elapses.
+	Set(ctx context.Context, prefix, key string, scopes []string, ttl time.Duration)
+	// Delete evicts a single key cached under prefix.
+	Delete(ctx context.Context, prefix, key string)
+	// InvalidatePrefix evicts every key cached for a resolver prefix, e.g. "dashboards:uid:" after a dashboard
+	// is renamed or deleted. Used to avoid serving stale resolutions until their TTL naturally expires.
+	InvalidatePrefix(ctx context.Context, prefix string)
+}
+
+// localScopeCache is the default ScopeResolutionCache, wrapping the existing in-memory cache service. It
+// additionally tracks which keys were cached under which prefix, and when each entry expires, so
+// InvalidatePrefix has something to evict without localcache.CacheService's help (it has no notion of key
+// prefixes on its own). Expired members are swept opportunistically on Set so the index can't grow without
+// bound for prefixes that are never explicitly invalidated.
+type localScopeCache struct {
+	cache *localcache.CacheService
+
+	mu           sync.Mutex
+	keysByPrefix map[string]map[string]time.Time // key -> expiresAt
+}
+
+func newLocalScopeCache(defaultTTL, cleanInterval time.Duration) *localScopeCache {
+	return &localScopeCache{
+		cache:        localcache.New(defaultTTL, cleanInterval),
+		keysByPrefix: map[string]map[string]time.Time{},
+	}
+}
+
+func (c *localScopeCache) Get(_ context.Context, _, key string) ([]string, bool) {
+	cached, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	scopes, ok := cached.([]string)
+	return scopes, ok
+}
+
+func (c *localScopeCache) Set(_ context.Context, prefix, key string, scopes []string, ttl time.Duration) {
+	c.cache.Set(key, scopes, ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	members, ok := c.keysByPrefix[prefix]
+	if !ok {
+		members = map[string]time.Time{}
+		c.keysByPrefix[prefix] = members
+	}
+	members[key] = time.Now().Add(ttl)
+	pruneExpiredMembersLocked(members)
+}
+
+func (c *localScopeCache) Delete(_ context.Context, _, key string) {
+	c.cache.Delete(key)
+}
+
+func (c *localScopeCache) InvalidatePrefix(_ context.Context, prefix string) {
+	c.mu.Lock()
+	members := c.keysByPrefix[prefix]
+	delete(c.keysByPrefix, prefix)
+	c.mu.Unlock()
+
+	for key := range members {
+		c.cache.Delete(key)
+	}
+}
+
+// pruneExpiredMembersLocked removes entries from members whose TTL has already elapsed. Callers must hold the
+// owning localScopeCache's mutex.
+func pruneExpiredMembersLocked(members map[string]time.Time) {
+	now := time.Now()
+	for key, expiresAt := range members {
+		if now.After(expiresAt) {
+			delete(members, key)
+		}
+	}
+}
+
+// remoteScopeCacheGenerationPrefix namespaces the per-prefix generation counters remoteScopeCache stores
+// alongside resolved scopes.
+const remoteScopeCacheGenerationPrefix = "accesscontrol-scope-generation:"
+
+// remoteGenerationTTL bounds how long a prefix's generation counter survives without being refreshed by an
+// InvalidatePrefix call, so a prefix that's never invalidated again doesn't pin an entry forever.
+const remoteGenerationTTL = 24 * time.Hour
+
+// remoteScopeCache is a ScopeResolutionCache backed by Grafana's remote cache service (Redis/memcached/Postgres),
+// giving cluster-wide cache coherency instead of each HA instance independently caching for up to ttl.
+//
+// InvalidatePrefix can't rely on a mutable index of "keys cached under this prefix": a read-modify-write
+// against the remote store (read the list, append, write it back) races across concurrent callers on
+// different nodes, and a lost update silently keeps serving a stale resolution past invalidation — exactly
+// the failure mode this cache exists to avoid. Instead, every key actually stored remotely is versioned with
+// a per-prefix generation counter, and InvalidatePrefix simply bumps that counter. Entries written under a
+// generation that's since been bumped become unreachable (future Get/Set address the new generation) and are
+// left to expire via their own TTL rather than being deleted eagerly; a Set racing a concurrent
+// InvalidatePrefix therefore never clobbers the invalidation, it just lands under the old or new generation
+// depending on ordering, both of which are safe outcomes.
+type remoteScopeCache struct {
+	client remotecache.CacheStorage
+	log    log.Logger
+}
+
+func newRemoteScopeCache(client remotecache.CacheStorage, log log.Logger) *remoteScopeCache {
+	return &remoteScopeCache{client: client, log: log}
+}
+
+func (c *remoteScopeCache) Get(ctx context.Context, prefix, key string) ([]string, bool) {
+	cached, err := c.client.Get(ctx, c.versionedKey(ctx, prefix, key))
+	if err != nil {
+		return nil, false
+	}
+	scopes, ok := cached.([]string)
+	return scopes, ok
+}
+
+func (c *remoteScopeCache) Set(ctx context.Context, prefix, key string, scopes []string, ttl time.Duration) {
+	if err := c.client.Set(ctx, c.versionedKey(ctx, prefix, key), scopes, ttl); err != nil {
+		c.log.Warn("Failed to cache scope resolution in remote cache", "key", key, "error", err)
+	}
+}
+
+func (c *remoteScopeCache) Delete(ctx context.Context, prefix, key string) {
+	if err := c.client.Delete(ctx, c.versionedKey(ctx, prefix, key)); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		c.log.Warn("Failed to delete scope resolution from remote cache", "key", key, "error", err)
+	}
+}
+
+// InvalidatePrefix bumps the generation counter for prefix, orphaning every key previously cached under it.
+// Orphaned entries are never deleted eagerly; they simply expire via their own TTL once nothing addresses
+// them anymore.
+func (c *remoteScopeCache) InvalidatePrefix(ctx context.Context, prefix string) {
+	next := c.generation(ctx, prefix) + 1
+	if err := c.client.Set(ctx, remoteScopeCacheGenerationPrefix+prefix, next, remoteGenerationTTL); err != nil {
+		c.log.Warn("Failed to bump remote scope cache generation", "prefix", prefix, "error", err)
+	}
+}
+
+// versionedKey folds prefix's current generation into key. prefix is always the caller-supplied resolver
+// prefix derived from the original scope, never derived from key itself, so a key carrying a ResolveOption
+// suffix still versions (and therefore invalidates) under its real prefix.
+func (c *remoteScopeCache) versionedKey(ctx context.Context, prefix, key string) string {
+	return fmt.Sprintf("%s@gen%d", key, c.generation(ctx, prefix))
+}
+
+func (c *remoteScopeCache) generation(ctx context.Context, prefix string) int64 {
+	cached, err := c.client.Get(ctx, remoteScopeCacheGenerationPrefix+prefix)
+	if err != nil {
+		return 0
+	}
+	gen, _ := cached.(int64)
+	return gen
+}
+
+// ResolverOption configures a Resolvers instance at construction time.
+type ResolverOption func(*Resolvers)
+
+// WithScopeCache overrides the default local ScopeResolutionCache with a caller-provided implementation.
+func WithScopeCache(cache ScopeResolutionCache) ResolverOption {
+	return func(r *Resolvers) {
+		r.cache = cache
+	}
+}
+
+// WithRemoteScopeCache caches scope and action-set resolutions in Grafana's remote cache service instead of
+// an in-memory one, so every node in an HA deployment shares resolutions and invalidations.
+func WithRemoteScopeCache(client remotecache.CacheStorage) ResolverOption {
+	return func(r *Resolvers) {
+		r.cache = newRemoteScopeCache(client, r.log)
+	}
+}
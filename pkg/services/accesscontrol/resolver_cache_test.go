@@ -0,0 +1,145 @@
+package accesscontrol
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+func TestLocalScopeCache_GetSetDelete(t *testing.T) {
+	c := newLocalScopeCache(time.Minute, time.Minute)
+	ctx := context.Background()
+
+	_, ok := c.Get(ctx, "dashboards:id:", "k")
+	require.False(t, ok)
+
+	c.Set(ctx, "dashboards:id:", "k", []string{"dashboards:uid:1"}, time.Minute)
+	scopes, ok := c.Get(ctx, "dashboards:id:", "k")
+	require.True(t, ok)
+	require.Equal(t, []string{"dashboards:uid:1"}, scopes)
+
+	c.Delete(ctx, "dashboards:id:", "k")
+	_, ok = c.Get(ctx, "dashboards:id:", "k")
+	require.False(t, ok)
+}
+
+func TestLocalScopeCache_InvalidatePrefixEvictsSuffixedKeys(t *testing.T) {
+	c := newLocalScopeCache(time.Minute, time.Minute)
+	ctx := context.Background()
+	prefix := "dashboards:id:"
+
+	// Keys carrying a ResolveOption-derived suffix (an action or a user UID) contain their own colons, which
+	// is exactly what broke prefix derivation from the key in the first place.
+	key := "dashboards:id:1-1-action:dashboards:read"
+	c.Set(ctx, prefix, key, []string{"dashboards:uid:1"}, time.Minute)
+
+	_, ok := c.Get(ctx, prefix, key)
+	require.True(t, ok)
+
+	c.InvalidatePrefix(ctx, prefix)
+
+	_, ok = c.Get(ctx, prefix, key)
+	require.False(t, ok, "a suffixed key should still be evicted by the prefix it was actually cached under")
+}
+
+func TestLocalScopeCache_SetPrunesExpiredMembers(t *testing.T) {
+	c := newLocalScopeCache(time.Minute, time.Minute)
+	ctx := context.Background()
+	prefix := "dashboards:id:"
+
+	c.Set(ctx, prefix, "expired", []string{"x"}, time.Minute)
+	c.mu.Lock()
+	c.keysByPrefix[prefix]["expired"] = time.Now().Add(-time.Minute)
+	c.mu.Unlock()
+
+	c.Set(ctx, prefix, "fresh", []string{"y"}, time.Minute)
+
+	c.mu.Lock()
+	_, expiredStillTracked := c.keysByPrefix[prefix]["expired"]
+	_, freshStillTracked := c.keysByPrefix[prefix]["fresh"]
+	c.mu.Unlock()
+
+	require.False(t, expiredStillTracked, "an expired member should be pruned by a later Set")
+	require.True(t, freshStillTracked)
+}
+
+// fakeRemoteCache is a minimal in-memory stand-in for remotecache.CacheStorage.
+type fakeRemoteCache struct {
+	mu      sync.Mutex
+	entries map[string]interface{}
+}
+
+func newFakeRemoteCache() *fakeRemoteCache {
+	return &fakeRemoteCache{entries: map[string]interface{}{}}
+}
+
+func (f *fakeRemoteCache) Get(_ context.Context, key string) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.entries[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeRemoteCache) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries[key] = value
+	return nil
+}
+
+func (f *fakeRemoteCache) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.entries, key)
+	return nil
+}
+
+func (f *fakeRemoteCache) Count(_ context.Context, _ string) (int64, error) {
+	return 0, nil
+}
+
+func TestRemoteScopeCache_InvalidatePrefixOrphansSuffixedKeys(t *testing.T) {
+	client := newFakeRemoteCache()
+	c := newRemoteScopeCache(client, log.NewNopLogger())
+	ctx := context.Background()
+	prefix := "dashboards:id:"
+
+	key := "dashboards:id:1-1-action:dashboards:read"
+	c.Set(ctx, prefix, key, []string{"dashboards:uid:1"}, time.Minute)
+
+	scopes, ok := c.Get(ctx, prefix, key)
+	require.True(t, ok)
+	require.Equal(t, []string{"dashboards:uid:1"}, scopes)
+
+	c.InvalidatePrefix(ctx, prefix)
+
+	_, ok = c.Get(ctx, prefix, key)
+	require.False(t, ok, "bumping the prefix's generation should orphan a key cached under the old generation")
+}
+
+func TestRemoteScopeCache_DifferentPrefixesAreIndependentlyInvalidated(t *testing.T) {
+	client := newFakeRemoteCache()
+	c := newRemoteScopeCache(client, log.NewNopLogger())
+	ctx := context.Background()
+
+	c.Set(ctx, "dashboards:id:", "d-key", []string{"dashboards:uid:1"}, time.Minute)
+	c.Set(ctx, "folders:id:", "f-key", []string{"folders:uid:1"}, time.Minute)
+
+	c.InvalidatePrefix(ctx, "dashboards:id:")
+
+	_, ok := c.Get(ctx, "dashboards:id:", "d-key")
+	require.False(t, ok)
+
+	scopes, ok := c.Get(ctx, "folders:id:", "f-key")
+	require.True(t, ok, "invalidating one prefix should not affect another")
+	require.Equal(t, []string{"folders:uid:1"}, scopes)
+}
@@ -0,0 +1,110 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ResolverErrorPolicy controls whether and how resolver errors are cached. Without it, a resolver that starts
+// failing (e.g. a datasource lookup timing out) is retried on every single permission check, which can turn a
+// transient backing-store blip into a thundering herd.
+type ResolverErrorPolicy struct {
+	// CacheErrors enables negative caching of resolver errors. Defaults to disabled.
+	CacheErrors bool
+	// MinBackoff is the TTL applied to the first cached error for a given key. Defaults to 1 second.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff applied to repeated failures for the same key. Defaults to 30
+	// seconds.
+	MaxBackoff time.Duration
+	// ShouldCache decides whether a given error should be cached. Defaults to caching everything except
+	// ErrResolverNotFound and context cancellation/deadline errors, since neither benefits from backoff.
+	ShouldCache func(err error) bool
+}
+
+func defaultShouldCacheResolverError(err error) bool {
+	return !errors.Is(err, ErrResolverNotFound) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+// WithResolverErrorPolicy applies policy, filling in defaults for unset backoff bounds and ShouldCache. When
+// policy.CacheErrors is set, resolver errors are negatively cached with jittered exponential backoff, so a
+// resolver that starts failing gets retried with increasing delay instead of on every call.
+func WithResolverErrorPolicy(policy ResolverErrorPolicy) ResolverOption {
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = time.Second
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 30 * time.Second
+	}
+	if policy.ShouldCache == nil {
+		policy.ShouldCache = defaultShouldCacheResolverError
+	}
+	return func(r *Resolvers) {
+		r.errPolicy = policy
+	}
+}
+
+// resolverErrorCache holds cached resolver errors, keyed the same way as the scope cache, with exponential
+// backoff tracked per key. It's kept separate from ScopeResolutionCache since that interface only ever stores
+// resolved scopes.
+type resolverErrorCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResolverError
+}
+
+type cachedResolverError struct {
+	err         error
+	expiresAt   time.Time
+	nextBackoff time.Duration
+}
+
+func newResolverErrorCache() *resolverErrorCache {
+	return &resolverErrorCache{entries: map[string]*cachedResolverError{}}
+}
+
+func (c *resolverErrorCache) get(key string) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.err, true
+}
+
+// recordFailure caches err for key, doubling the backoff applied for repeated failures on the same key up to
+// max, with +/-20% jitter applied so many nodes failing the same resolver don't retry in lockstep.
+func (c *resolverErrorCache) recordFailure(key string, err error, minBackoff, maxBackoff time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	backoff := minBackoff
+	if entry, ok := c.entries[key]; ok {
+		backoff = entry.nextBackoff * 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	jittered := time.Duration(float64(backoff) * (0.8 + 0.4*rand.Float64()))
+	c.entries[key] = &cachedResolverError{
+		err:         err,
+		expiresAt:   time.Now().Add(jittered),
+		nextBackoff: backoff,
+	}
+}
+
+func (c *resolverErrorCache) clear(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,65 @@
+package accesscontrol
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverErrorCache_BackoffDoublesAndCapsAtMax(t *testing.T) {
+	c := newResolverErrorCache()
+	key := "dashboards:id:1-1"
+	minBackoff, maxBackoff := time.Second, 4*time.Second
+	testErr := errors.New("boom")
+
+	c.recordFailure(key, testErr, minBackoff, maxBackoff)
+	require.Equal(t, minBackoff, c.entries[key].nextBackoff)
+
+	c.recordFailure(key, testErr, minBackoff, maxBackoff)
+	require.Equal(t, 2*time.Second, c.entries[key].nextBackoff)
+
+	c.recordFailure(key, testErr, minBackoff, maxBackoff)
+	require.Equal(t, maxBackoff, c.entries[key].nextBackoff, "backoff should be capped at maxBackoff")
+
+	c.recordFailure(key, testErr, minBackoff, maxBackoff)
+	require.Equal(t, maxBackoff, c.entries[key].nextBackoff, "backoff should stay capped on further failures")
+}
+
+func TestResolverErrorCache_JitterStaysWithinTwentyPercent(t *testing.T) {
+	c := newResolverErrorCache()
+	key := "k"
+	backoff := 10 * time.Second
+
+	before := time.Now()
+	c.recordFailure(key, errors.New("boom"), backoff, backoff)
+	ttl := c.entries[key].expiresAt.Sub(before)
+
+	require.GreaterOrEqual(t, ttl, time.Duration(float64(backoff)*0.8))
+	require.LessOrEqual(t, ttl, time.Duration(float64(backoff)*1.2)+50*time.Millisecond)
+}
+
+func TestResolverErrorCache_GetExpires(t *testing.T) {
+	c := newResolverErrorCache()
+	key := "k"
+	c.recordFailure(key, errors.New("boom"), time.Second, time.Second)
+	c.entries[key].expiresAt = time.Now().Add(-time.Minute)
+
+	_, ok := c.get(key)
+	require.False(t, ok, "expired entry should no longer be served")
+}
+
+func TestResolverErrorCache_ClearRemovesEntry(t *testing.T) {
+	c := newResolverErrorCache()
+	key := "k"
+	c.recordFailure(key, errors.New("boom"), time.Second, 4*time.Second)
+
+	_, ok := c.get(key)
+	require.True(t, ok)
+
+	c.clear(key)
+
+	_, ok = c.get(key)
+	require.False(t, ok, "a cleared entry should not be served, e.g. after a subsequent success")
+}
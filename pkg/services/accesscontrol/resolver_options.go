@@ -0,0 +1,84 @@
+package accesscontrol
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// ResolveOptions carries the per-call hints collected from a ResolveOption list.
+type ResolveOptions struct {
+	// User is the identity the scope is being resolved on behalf of, letting a resolver narrow its result to
+	// what the caller can read (e.g. a folder resolver returning only folders the caller can see).
+	User identity.Requester
+	// Action is the permission action the scope is being resolved for.
+	Action string
+	// SkipCache bypasses the cache lookup for this call. The result is still cached on return.
+	SkipCache bool
+	// MaxResults, if set, is passed on as a cap for resolvers that support it. Resolvers does not truncate
+	// the resolved scopes itself.
+	MaxResults int
+}
+
+// ResolveOption configures a ResolveOptions for a single resolve call.
+type ResolveOption func(*ResolveOptions)
+
+// WithUser passes the identity a scope is being resolved on behalf of.
+func WithUser(user identity.Requester) ResolveOption {
+	return func(o *ResolveOptions) {
+		o.User = user
+	}
+}
+
+// WithAction passes the permission action a scope is being resolved for.
+func WithAction(action string) ResolveOption {
+	return func(o *ResolveOptions) {
+		o.Action = action
+	}
+}
+
+// WithSkipCache bypasses the cache lookup for this resolve call. The resolved scopes are still cached on
+// return.
+func WithSkipCache() ResolveOption {
+	return func(o *ResolveOptions) {
+		o.SkipCache = true
+	}
+}
+
+// WithMaxResults caps the number of scopes a resolver that supports it should return.
+func WithMaxResults(n int) ResolveOption {
+	return func(o *ResolveOptions) {
+		o.MaxResults = n
+	}
+}
+
+// ScopeAttributeOptionsResolver may optionally be implemented by a ScopeAttributeResolver that wants access to
+// the calling user, requested action, or other resolve-time hints. Resolvers that don't need them can keep
+// implementing just Resolve.
+//
+// This is an optional interface detected with a type assertion rather than an additional required method on
+// ScopeAttributeResolver itself (which would have made Resolve a shim calling ResolveWithOptions with
+// defaults). Both shapes keep existing resolvers compiling unmodified; this one was chosen to match the
+// optional-interface pattern already used for ScopeAttributeBatchResolver, at the cost of per-user scope
+// narrowing (e.g. a folder resolver returning only folders the caller can read) not actually being wired up
+// anywhere in this package yet -- no resolver registered here implements it.
+type ScopeAttributeOptionsResolver interface {
+	ResolveWithOptions(ctx context.Context, orgID int64, scope string, opts ...ResolveOption) ([]string, error)
+}
+
+// cacheKeySuffix returns the part of the cache key contributed by options that affect the resolved output, so
+// two calls for the same scope with different users, actions, or result limits don't collide in the cache.
+func (o ResolveOptions) cacheKeySuffix() string {
+	suffix := ""
+	if o.User != nil {
+		suffix += "-user:" + o.User.GetUID()
+	}
+	if o.Action != "" {
+		suffix += "-action:" + o.Action
+	}
+	if o.MaxResults > 0 {
+		suffix += fmt.Sprintf("-max:%d", o.MaxResults)
+	}
+	return suffix
+}
@@ -0,0 +1,113 @@
+package accesscontrol
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	resolverPoolGCInterval = 5 * time.Minute
+	resolverPoolMaxIdle    = 10 * time.Minute
+)
+
+// handleEntry tracks a single org-scoped resolver registration alongside when it was last consulted, so the
+// pool's background GC can evict entries nothing has used recently.
+type handleEntry struct {
+	resolver ScopeAttributeResolver
+	lastUsed time.Time
+}
+
+// resolverNS holds the org-scoped resolvers registered for a single namespace (org), keyed by prefix.
+type resolverNS struct {
+	mu       sync.Mutex
+	byPrefix map[string]*handleEntry
+}
+
+func newResolverNS() *resolverNS {
+	return &resolverNS{byPrefix: map[string]*handleEntry{}}
+}
+
+func (ns *resolverNS) add(prefix string, resolver ScopeAttributeResolver) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.byPrefix[prefix] = &handleEntry{resolver: resolver, lastUsed: time.Now()}
+}
+
+func (ns *resolverNS) get(prefix string) (ScopeAttributeResolver, bool) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	entry, ok := ns.byPrefix[prefix]
+	if !ok {
+		return nil, false
+	}
+	entry.lastUsed = time.Now()
+	return entry.resolver, true
+}
+
+// gc drops entries unused for longer than maxIdle and reports whether the namespace is now empty, so the
+// caller can prune it too.
+func (ns *resolverNS) gc(maxIdle time.Duration) (empty bool) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	now := time.Now()
+	for prefix, entry := range ns.byPrefix {
+		if now.Sub(entry.lastUsed) > maxIdle {
+			delete(ns.byPrefix, prefix)
+		}
+	}
+	return len(ns.byPrefix) == 0
+}
+
+// resolverPool is a two-level registry of org-scoped scope attribute resolvers: map[namespace]*resolverNS,
+// where namespace is an org ID. It exists alongside the global attributeResolvers map on Resolvers so
+// multi-tenant features (e.g. plugin-provided scope kinds) can register resolvers that come and go with a
+// tenant, without leaking memory in long-running instances.
+type resolverPool struct {
+	mu         sync.Mutex
+	namespaces map[int64]*resolverNS
+}
+
+func newResolverPool() *resolverPool {
+	return &resolverPool{namespaces: map[int64]*resolverNS{}}
+}
+
+func (p *resolverPool) add(orgID int64, prefix string, resolver ScopeAttributeResolver) {
+	ns := p.namespace(orgID)
+	ns.add(prefix, resolver)
+}
+
+func (p *resolverPool) get(orgID int64, prefix string) (ScopeAttributeResolver, bool) {
+	p.mu.Lock()
+	ns, ok := p.namespaces[orgID]
+	p.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return ns.get(prefix)
+}
+
+func (p *resolverPool) namespace(orgID int64) *resolverNS {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ns, ok := p.namespaces[orgID]
+	if !ok {
+		ns = newResolverNS()
+		p.namespaces[orgID] = ns
+	}
+	return ns
+}
+
+// gc prunes idle resolver entries across every namespace, and removes namespaces left empty as a result.
+func (p *resolverPool) gc(maxIdle time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for orgID, ns := range p.namespaces {
+		if ns.gc(maxIdle) {
+			delete(p.namespaces, orgID)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package accesscontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func noopScopeResolver(_ context.Context, _ int64, scope string) ([]string, error) {
+	return []string{scope}, nil
+}
+
+func TestResolverNS_GCEvictsOnlyIdleEntries(t *testing.T) {
+	ns := newResolverNS()
+	ns.add("dashboards:id:", ScopeAttributeResolverFunc(noopScopeResolver))
+	ns.add("folders:id:", ScopeAttributeResolverFunc(noopScopeResolver))
+
+	ns.byPrefix["dashboards:id:"].lastUsed = time.Now().Add(-time.Hour)
+
+	empty := ns.gc(10 * time.Minute)
+	require.False(t, empty)
+
+	_, ok := ns.get("dashboards:id:")
+	require.False(t, ok, "idle entry should have been evicted")
+
+	_, ok = ns.get("folders:id:")
+	require.True(t, ok, "recently used entry should survive")
+}
+
+func TestResolverNS_GCReportsEmpty(t *testing.T) {
+	ns := newResolverNS()
+	ns.add("dashboards:id:", ScopeAttributeResolverFunc(noopScopeResolver))
+	ns.byPrefix["dashboards:id:"].lastUsed = time.Now().Add(-time.Hour)
+
+	require.True(t, ns.gc(10*time.Minute))
+}
+
+func TestResolverPool_GCPrunesIdleEntriesAndEmptyNamespaces(t *testing.T) {
+	p := newResolverPool()
+	p.add(1, "dashboards:id:", ScopeAttributeResolverFunc(noopScopeResolver))
+	p.add(2, "folders:id:", ScopeAttributeResolverFunc(noopScopeResolver))
+
+	p.namespace(1).byPrefix["dashboards:id:"].lastUsed = time.Now().Add(-time.Hour)
+
+	p.gc(10 * time.Minute)
+
+	p.mu.Lock()
+	_, orgOneStillPresent := p.namespaces[1]
+	_, orgTwoStillPresent := p.namespaces[2]
+	p.mu.Unlock()
+
+	require.False(t, orgOneStillPresent, "namespace left empty by GC should be pruned")
+	require.True(t, orgTwoStillPresent, "namespace with a recently used entry should survive")
+
+	_, ok := p.get(2, "folders:id:")
+	require.True(t, ok)
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 )
 
 // ScopeAttributeResolver is used to resolve attributes in scopes to one or more scopes that are
 // evaluated by logical or. E.g. "dashboards:id:1" -> "dashboards:uid:test-dashboard" or "folder:uid:test-folder"
+//
+// A resolver that needs the calling user, the requested action, or other resolve-time hints can additionally
+// implement ScopeAttributeOptionsResolver.
 type ScopeAttributeResolver interface {
 	Resolve(ctx context.Context, orgID int64, scope string) ([]string, error)
 }
@@ -37,19 +39,33 @@ const (
 	cleanInterval = 2 * time.Minute
 )
 
-func NewResolvers(log log.Logger) Resolvers {
-	return Resolvers{
+func NewResolvers(log log.Logger, opts ...ResolverOption) Resolvers {
+	r := Resolvers{
 		log:                log,
-		cache:              localcache.New(ttl, cleanInterval),
 		attributeResolvers: map[string]ScopeAttributeResolver{},
+		orgResolvers:       newResolverPool(),
+		errCache:           newResolverErrorCache(),
+		stop:               make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if r.cache == nil {
+		r.cache = newLocalScopeCache(ttl, cleanInterval)
+	}
+	go r.runResolverPoolGC()
+	return r
 }
 
 type Resolvers struct {
 	log                log.Logger
-	cache              *localcache.CacheService
+	cache              ScopeResolutionCache
 	attributeResolvers map[string]ScopeAttributeResolver
+	orgResolvers       *resolverPool
 	actionResolver     ActionResolver
+	errPolicy          ResolverErrorPolicy
+	errCache           *resolverErrorCache
+	stop               chan struct{}
 }
 
 func (s *Resolvers) AddScopeAttributeResolver(prefix string, resolver ScopeAttributeResolver) {
@@ -57,28 +73,89 @@ func (s *Resolvers) AddScopeAttributeResolver(prefix string, resolver ScopeAttri
 	s.attributeResolvers[prefix] = resolver
 }
 
+// AddScopeAttributeResolverForOrg registers a resolver scoped to a single org, e.g. one provided by a plugin.
+// GetScopeAttributeMutator consults org-scoped resolvers before falling back to globally registered ones.
+// Unlike the global registry, org-scoped resolvers unused for resolverPoolMaxIdle are pruned by a background
+// goroutine, so plugins and tenants that come and go don't leak resolvers in long-running instances.
+func (s *Resolvers) AddScopeAttributeResolverForOrg(orgID int64, prefix string, resolver ScopeAttributeResolver) {
+	s.log.Debug("Adding org-scoped scope attribute resolver", "orgId", orgID, "prefix", prefix)
+	s.orgResolvers.add(orgID, prefix, resolver)
+}
+
 func (s *Resolvers) SetActionResolver(resolver ActionResolver) {
 	s.actionResolver = resolver
 }
 
-func (s *Resolvers) GetScopeAttributeMutator(orgID int64) ScopeAttributeMutator {
-	return func(ctx context.Context, scope string) ([]string, error) {
-		key := getScopeCacheKey(orgID, scope)
-		// Check cache before computing the scope
-		if cachedScope, ok := s.cache.Get(key); ok {
-			scopes := cachedScope.([]string)
-			s.log.Debug("Used cache to resolve scope", "scope", scope, "resolved_scopes", scopes)
-			return scopes, nil
+// resolverFor looks up the resolver registered for prefix, preferring one scoped to orgID over the globally
+// registered one.
+func (s *Resolvers) resolverFor(orgID int64, prefix string) (ScopeAttributeResolver, bool) {
+	if resolver, ok := s.orgResolvers.get(orgID, prefix); ok {
+		return resolver, true
+	}
+	resolver, ok := s.attributeResolvers[prefix]
+	return resolver, ok
+}
+
+// runResolverPoolGC periodically prunes org-scoped resolvers that haven't been used recently, until Close is
+// called.
+func (s *Resolvers) runResolverPoolGC() {
+	ticker := time.NewTicker(resolverPoolGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.orgResolvers.gc(resolverPoolMaxIdle)
+		case <-s.stop:
+			return
 		}
+	}
+}
+
+// Close stops the background goroutine that prunes idle org-scoped resolvers.
+func (s *Resolvers) Close() {
+	close(s.stop)
+}
+
+// GetScopeAttributeMutator returns a mutator that resolves scopes for orgID. Any ResolveOption passed applies
+// to every scope resolved by the returned mutator; options that affect the resolved output (WithUser,
+// WithAction, WithMaxResults) are folded into the cache key so they don't collide with calls made without them.
+func (s *Resolvers) GetScopeAttributeMutator(orgID int64, opts ...ResolveOption) ScopeAttributeMutator {
+	var options ResolveOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
+	return func(ctx context.Context, scope string) ([]string, error) {
 		prefix := ScopePrefix(scope)
-		if resolver, ok := s.attributeResolvers[prefix]; ok {
-			scopes, err := resolver.Resolve(ctx, orgID, scope)
+		key := getScopeCacheKey(orgID, scope) + options.cacheKeySuffix()
+		if !options.SkipCache {
+			// Check cache before computing the scope
+			if scopes, ok := s.cache.Get(ctx, prefix, key); ok {
+				s.log.Debug("Used cache to resolve scope", "scope", scope, "resolved_scopes", scopes)
+				return scopes, nil
+			}
+			if s.errPolicy.CacheErrors {
+				if cachedErr, ok := s.errCache.get(key); ok {
+					return nil, cachedErr
+				}
+			}
+		}
+
+		if resolver, ok := s.resolverFor(orgID, prefix); ok {
+			scopes, err := resolve(ctx, resolver, orgID, scope, opts...)
 			if err != nil {
-				return nil, fmt.Errorf("could not resolve %v: %w", scope, err)
+				wrapped := fmt.Errorf("could not resolve %v: %w", scope, err)
+				if s.errPolicy.CacheErrors && s.errPolicy.ShouldCache(err) {
+					s.errCache.recordFailure(key, wrapped, s.errPolicy.MinBackoff, s.errPolicy.MaxBackoff)
+				}
+				return nil, wrapped
+			}
+			if s.errPolicy.CacheErrors {
+				s.errCache.clear(key)
 			}
 			// Cache result
-			s.cache.Set(key, scopes, ttl)
+			s.cache.Set(ctx, prefix, key, scopes, ttl)
 			s.log.Debug("Resolved scope", "scope", scope, "resolved_scopes", scopes)
 			return scopes, nil
 		}
@@ -86,19 +163,55 @@ func (s *Resolvers) GetScopeAttributeMutator(orgID int64) ScopeAttributeMutator
 	}
 }
 
+// resolve calls ResolveWithOptions when resolver implements ScopeAttributeOptionsResolver, and otherwise
+// falls back to Resolve, ignoring opts.
+func resolve(ctx context.Context, resolver ScopeAttributeResolver, orgID int64, scope string, opts ...ResolveOption) ([]string, error) {
+	if optsResolver, ok := resolver.(ScopeAttributeOptionsResolver); ok {
+		return optsResolver.ResolveWithOptions(ctx, orgID, scope, opts...)
+	}
+	return resolver.Resolve(ctx, orgID, scope)
+}
+
 // getScopeCacheKey creates an identifier to fetch and store resolution of scopes in the cache
 func getScopeCacheKey(orgID int64, scope string) string {
 	return fmt.Sprintf("%s-%v", scope, orgID)
 }
 
+// getActionSetCacheKey creates an identifier to fetch and store resolution of an action's action-set
+// expansion in the cache.
+func getActionSetCacheKey(action string) string {
+	return fmt.Sprintf("actionset-%s", action)
+}
+
+// actionSetCachePrefix is the ScopeResolutionCache prefix under which action-set expansions are cached.
+// Action-set entries have no resolver prefix of their own (they're keyed by action, not scope), so they all
+// share this one stand-in prefix; InvalidatePrefix is never called with it today since action-set resolution
+// doesn't change underneath a running instance the way a scope resolution can.
+const actionSetCachePrefix = "actionset:"
+
 func (s *Resolvers) GetActionSetResolver() ActionSetResolver {
 	return func(ctx context.Context, action string) []string {
 		if s.actionResolver == nil {
 			return []string{action}
 		}
+
+		key := getActionSetCacheKey(action)
+		if actions, ok := s.cache.Get(ctx, actionSetCachePrefix, key); ok {
+			s.log.Debug("Used cache to resolve action", "action", action, "resolved_actions", actions)
+			return actions
+		}
+
 		actionSetActions := s.actionResolver.ResolveAction(action)
 		actions := append(actionSetActions, action)
+		s.cache.Set(ctx, actionSetCachePrefix, key, actions, ttl)
 		s.log.Debug("Resolved action", "action", action, "resolved_actions", actions)
 		return actions
 	}
 }
+
+// InvalidateResourceScopes evicts every cached scope resolution rooted at prefix (e.g. "dashboards:uid:").
+// Stores that mutate a resource referenced by a scope resolver should call this after a write so other nodes
+// in an HA deployment don't keep serving a stale resolution until it naturally expires.
+func (s *Resolvers) InvalidateResourceScopes(ctx context.Context, prefix string) {
+	s.cache.InvalidatePrefix(ctx, prefix)
+}